@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/a-h/generate"
+)
+
+// runExtract implements the -extract mode: it derives a JSON Schema from
+// the annotated Go structs found in pkgDir and writes it to outPath, or
+// stdout when outPath is empty. It returns the process exit code.
+func runExtract(pkgDir, outPath string) int {
+	schema, err := generate.ExtractSchema(pkgDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to extract a schema with error ", err)
+		return 1
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to marshal the extracted schema with error ", err)
+		return 1
+	}
+
+	w := os.Stdout
+	if outPath != "" {
+		f, err := os.Create(outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error opening output file: ", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	fmt.Fprintln(w, string(data))
+	return 0
+}
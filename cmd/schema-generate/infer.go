@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/a-h/generate/jsonschema"
+)
+
+// maxInferredEnumValues bounds how many distinct string values a property
+// can take across an array of examples before infer gives up trying to
+// describe it as an enum, mirroring what helm-values-schema-json does for
+// Helm chart values.
+const maxInferredEnumValues = 5
+
+// runInfer implements the `infer` subcommand: it reads a YAML or JSON
+// example values document and emits the JSON Schema that describes its
+// shape, suitable for feeding straight back into schema-generate.
+func runInfer(args []string) int {
+	fs := flag.NewFlagSet("infer", flag.ExitOnError)
+	out := fs.String("o", "", "The output file for the inferred schema.")
+	fs.Parse(args)
+
+	rest := fs.Args()
+	if len(rest) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: schema-generate infer [-o FILE] <values-file>")
+		return 1
+	}
+
+	file := rest[0]
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read the input file with error ", err)
+		return 1
+	}
+
+	if isYAMLFile(file) {
+		b, err = yamlToJSON(b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to convert YAML input file %s: %v\n", file, err)
+			return 1
+		}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to parse the input values document with error ", err)
+		return 1
+	}
+
+	schema := inferSchema(value)
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to marshal the inferred schema with error ", err)
+		return 1
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error opening output file: ", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	fmt.Fprintln(w, string(data))
+	return 0
+}
+
+// inferSchema builds a jsonschema.Schema that describes value, recursing
+// into objects and arrays. A field is "required" when it's present on the
+// example object - there's only ever one example per object here, so
+// presence is the only signal available.
+func inferSchema(value interface{}) *jsonschema.Schema {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s := &jsonschema.Schema{Type: "object", Properties: make(map[string]*jsonschema.Schema)}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			s.Properties[name] = inferSchema(v[name])
+			s.Required = append(s.Required, name)
+		}
+		return s
+	case []interface{}:
+		s := &jsonschema.Schema{Type: "array"}
+		if len(v) == 0 {
+			return s
+		}
+		s.Items = mergeSchemas(v)
+		inferArrayEnum(s.Items, v)
+		return s
+	case string:
+		return &jsonschema.Schema{Type: "string"}
+	case float64:
+		if v == float64(int64(v)) {
+			return &jsonschema.Schema{Type: "integer"}
+		}
+		return &jsonschema.Schema{Type: "number"}
+	case bool:
+		return &jsonschema.Schema{Type: "boolean"}
+	default:
+		return &jsonschema.Schema{Type: "null"}
+	}
+}
+
+// mergeSchemas infers a schema for every element of an array and folds
+// them into one, so that e.g. an array of objects with slightly different
+// fields still produces a single item schema with the union of properties.
+func mergeSchemas(values []interface{}) *jsonschema.Schema {
+	var merged *jsonschema.Schema
+	for _, v := range values {
+		s := inferSchema(v)
+		if merged == nil {
+			merged = s
+			continue
+		}
+		merged = mergeSchema(merged, s)
+	}
+	return merged
+}
+
+func mergeSchema(a, b *jsonschema.Schema) *jsonschema.Schema {
+	if a.Type != b.Type {
+		// Mixed-type arrays can't be described more precisely than this.
+		return &jsonschema.Schema{Type: "object"}
+	}
+	if a.Properties == nil {
+		return a
+	}
+	for name, schema := range b.Properties {
+		if _, ok := a.Properties[name]; !ok {
+			a.Properties[name] = schema
+			continue
+		}
+	}
+	// A property is only required if every element of the array had it.
+	required := a.Required[:0]
+	for _, name := range a.Required {
+		if contains(b.Required, name) {
+			required = append(required, name)
+		}
+	}
+	a.Required = required
+	return a
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// inferArrayEnum looks for a string property that only ever takes a small
+// number of distinct values across the array's elements, and records them
+// as an enum.
+func inferArrayEnum(itemSchema *jsonschema.Schema, values []interface{}) {
+	if itemSchema == nil || itemSchema.Type != "object" {
+		return
+	}
+
+	for name, propSchema := range itemSchema.Properties {
+		if propSchema.Type != "string" {
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, v := range values {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			s, ok := obj[name].(string)
+			if !ok {
+				continue
+			}
+			seen[s] = true
+			if len(seen) > maxInferredEnumValues {
+				break
+			}
+		}
+
+		if len(seen) == 0 || len(seen) > maxInferredEnumValues {
+			continue
+		}
+
+		distinct := make([]string, 0, len(seen))
+		for s := range seen {
+			distinct = append(distinct, s)
+		}
+		sort.Strings(distinct)
+
+		propSchema.Enum = make([]interface{}, len(distinct))
+		for i, s := range distinct {
+			propSchema.Enum[i] = s
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestInferSchemaObject makes sure inferSchema derives an object schema
+// with every observed property marked required, since there's only ever
+// one example per object to go on.
+func TestInferSchemaObject(t *testing.T) {
+	value := map[string]interface{}{
+		"name":  "widget",
+		"count": float64(3),
+	}
+
+	schema := inferSchema(value)
+
+	if schema.Type != "object" {
+		t.Fatalf("expected type object, got %q", schema.Type)
+	}
+	if schema.Properties["name"].Type != "string" {
+		t.Errorf("expected name to be string, got %q", schema.Properties["name"].Type)
+	}
+	if schema.Properties["count"].Type != "integer" {
+		t.Errorf("expected count to be integer, got %q", schema.Properties["count"].Type)
+	}
+	if !contains(schema.Required, "name") || !contains(schema.Required, "count") {
+		t.Errorf("expected both properties to be required, got %v", schema.Required)
+	}
+}
+
+// TestInferSchemaArrayEnum makes sure a string property that only takes a
+// small number of distinct values across an array of examples is recorded
+// as an enum.
+func TestInferSchemaArrayEnum(t *testing.T) {
+	value := []interface{}{
+		map[string]interface{}{"status": "on"},
+		map[string]interface{}{"status": "off"},
+		map[string]interface{}{"status": "on"},
+	}
+
+	schema := inferSchema(value)
+
+	if schema.Type != "array" {
+		t.Fatalf("expected type array, got %q", schema.Type)
+	}
+
+	statusSchema := schema.Items.Properties["status"]
+	if len(statusSchema.Enum) != 2 {
+		t.Fatalf("expected 2 distinct enum values, got %v", statusSchema.Enum)
+	}
+}
+
+// TestMergeSchemaRequiredIntersection makes sure a property is only kept
+// required after merging when every element of the array had it.
+func TestMergeSchemaRequiredIntersection(t *testing.T) {
+	value := []interface{}{
+		map[string]interface{}{"name": "a", "extra": "x"},
+		map[string]interface{}{"name": "b"},
+	}
+
+	schema := inferSchema(value)
+
+	items := schema.Items
+	if !contains(items.Required, "name") {
+		t.Errorf("expected name to remain required, got %v", items.Required)
+	}
+	if contains(items.Required, "extra") {
+		t.Errorf("expected extra to no longer be required, got %v", items.Required)
+	}
+	if _, ok := items.Properties["extra"]; !ok {
+		t.Errorf("expected extra to still be present as a property, got %v", items.Properties)
+	}
+}
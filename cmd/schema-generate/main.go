@@ -23,18 +23,73 @@ var (
 	o = flag.String("o", "", "The output file for the schema.")
 	p = flag.String("p", "main", "The package that the structs are created in.")
 	i = flag.String("i", "", "A single file path (used for backwards compatibility).")
+
+	validateJSON = flag.Bool("json", false, "With validate, emit the validation errors as JSON instead of text.")
+
+	tags = flag.String("tags", "", "Comma-separated extra tag styles to emit alongside json, e.g. \"validate,binding\". When set, a Validate() method is also generated for each struct with constraints.")
+
+	templateFile = flag.String("template", "", "A text/template file used to render the output, in place of the default templates.")
+	templateDir  = flag.String("template-dir", "", "A directory of text/template files used to render the output, in place of the default templates.")
+
+	schemaPackages = make(mapFlag)
+	schemaOutputs  = make(mapFlag)
+
+	yamlInput = flag.Bool("yaml", false, "Treat all input files as YAML, regardless of extension.")
+
+	extract = flag.String("extract", "", "Extract a JSON Schema from the annotated Go structs in this package directory, instead of generating structs from a schema.")
 )
 
+func init() {
+	flag.Var(schemaPackages, "schema-package", "Route a schema's structs into another package: -schema-package=URI=pkg. Repeatable; URI must match an input path.")
+	flag.Var(schemaOutputs, "schema-output", "Write a schema's package to a specific file: -schema-output=URI=path. Repeatable; URI must match an input path.")
+}
+
+// mapFlag implements flag.Value for repeatable "URI=value" flags such as
+// -schema-package and -schema-output.
+type mapFlag map[string]string
+
+func (m mapFlag) String() string {
+	return fmt.Sprintf("%v", map[string]string(m))
+}
+
+func (m mapFlag) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected URI=value, got %q", s)
+	}
+	m[parts[0]] = parts[1]
+	return nil
+}
+
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
 		flag.PrintDefaults()
 		fmt.Fprintln(os.Stderr, "  paths")
 		fmt.Fprintln(os.Stderr, "\tThe input JSON Schema files.")
+		fmt.Fprintln(os.Stderr, "\nSubcommands:")
+		fmt.Fprintln(os.Stderr, "  validate <schema> <instance...>")
+		fmt.Fprintln(os.Stderr, "\tValidates one or more JSON instance documents against <schema>.")
+		fmt.Fprintln(os.Stderr, "\tEach instance may be inline JSON or, prefixed with @, a file path.")
+		fmt.Fprintln(os.Stderr, "  infer [-o FILE] <values-file>")
+		fmt.Fprintln(os.Stderr, "\tInfers a JSON Schema from a YAML or JSON example values document.")
+		fmt.Fprintln(os.Stderr, "  -extract=DIR [-o FILE]")
+		fmt.Fprintln(os.Stderr, "\tExtracts a JSON Schema from the annotated Go structs in package DIR.")
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "infer" {
+		os.Exit(runInfer(os.Args[2:]))
 	}
 
 	flag.Parse()
 
+	if *extract != "" {
+		os.Exit(runExtract(*extract, *o))
+	}
+
 	inputFiles := flag.Args()
 	if *i != "" {
 		inputFiles = append(inputFiles, *i)
@@ -53,7 +108,18 @@ func main() {
 			return
 		}
 
+		if isYAMLFile(file) {
+			b, err = yamlToJSON(b)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to convert YAML input file %s: %v\n", file, err)
+				return
+			}
+		}
+
 		schemas[i], err = jsonschema.Parse(string(b))
+		if err == nil {
+			schemas[i].URI = file
+		}
 		if err != nil {
 			if jsonError, ok := err.(*json.SyntaxError); ok {
 				line, character, lcErr := lineAndCharacter(b, int(jsonError.Offset))
@@ -77,24 +143,131 @@ func main() {
 	}
 
 	g := generate.New(schemas...)
+	g.SetSchemaPackages(schemaPackages)
 
-	structs, aliases, err := g.CreateTypes()
+	packages, err := g.CreateTypes()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Failure generating structs: ", err)
+		os.Exit(1)
+	}
+
+	// Map each output package back to a schema-output path, so that
+	// -schema-output=URI=path applies to whichever package that URI's
+	// schema was routed into.
+	pkgOutputPaths := make(map[string]string)
+	for _, file := range inputFiles {
+		pkg := schemaPackages[file]
+		if _, ok := pkgOutputPaths[pkg]; ok {
+			continue
+		}
+		if path, ok := schemaOutputs[file]; ok {
+			pkgOutputPaths[pkg] = path
+		}
 	}
 
-	var w io.Writer = os.Stdout
+	for pkgKey, po := range packages {
+		goPackage := *p
+		if pkgKey != generate.DefaultPackage {
+			goPackage = generate.PackageAlias(pkgKey)
+		}
 
-	if *o != "" {
-		w, err = os.Create(*o)
+		outPath := pkgOutputPaths[pkgKey]
+		if outPath == "" && pkgKey == generate.DefaultPackage {
+			outPath = *o
+		}
 
+		var w io.Writer = os.Stdout
+		if outPath != "" {
+			f, err := os.Create(outPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error opening output file: ", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		output(w, goPackage, po)
+	}
+}
+
+// runValidate implements the `validate` subcommand: it loads a JSON schema
+// and one or more JSON instance documents and reports any constraint
+// violations found, using lineAndCharacter to translate each error's byte
+// offset into a human-friendly position. It returns the process exit code.
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Emit the validation errors as JSON instead of text.")
+	fs.Parse(args)
+
+	rest := fs.Args()
+	if len(rest) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: schema-generate validate <schema> <instance...>")
+		return 1
+	}
+
+	schemaArg, instanceArgs := rest[0], rest[1:]
+
+	schemaBytes, err := loadJSONFlag(schemaArg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load schema: ", err)
+		return 1
+	}
+
+	schema, err := jsonschema.Parse(string(schemaBytes))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to parse schema: ", err)
+		return 1
+	}
+
+	exitCode := 0
+	for _, instanceArg := range instanceArgs {
+		instance, err := loadJSONFlag(instanceArg)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error opening output file: ", err)
-			return
+			fmt.Fprintf(os.Stderr, "Failed to load instance %q: %v\n", instanceArg, err)
+			exitCode = 1
+			continue
+		}
+
+		errs, err := generate.Validate(schema, instance)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to validate %q: %v\n", instanceArg, err)
+			exitCode = 1
+			continue
+		}
+		if len(errs) == 0 {
+			continue
+		}
+
+		exitCode = 1
+		if *asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(errs)
+			continue
+		}
+
+		for _, e := range errs {
+			line, character, lcErr := lineAndCharacter(instance, e.Offset)
+			if lcErr != nil {
+				fmt.Fprintf(os.Stdout, "%s: %s: %s\n", instanceArg, e.Path, e.Message)
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "%s:%d:%d: %s: %s\n", instanceArg, line, character, e.Path, e.Message)
 		}
 	}
 
-	output(w, structs, aliases)
+	return exitCode
+}
+
+// loadJSONFlag loads a chunk of JSON either from a file, when s is prefixed
+// with @, or inline from the argument itself, mirroring the convention used
+// by formidable's loadJSONFlag helper.
+func loadJSONFlag(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "@") {
+		return ioutil.ReadFile(s[1:])
+	}
+	return []byte(s), nil
 }
 
 func lineAndCharacter(bytes []byte, offset int) (line int, character int, err error) {
@@ -143,49 +316,25 @@ func getOrderedStructNames(m map[string]generate.Struct) []string {
 	return keys
 }
 
-func output(w io.Writer, structs map[string]generate.Struct, aliases map[string]generate.Field) {
-	buf := &bytes.Buffer{}
-
-	fmt.Fprintln(buf, "// Code generated by schema-generate. DO NOT EDIT.")
-	fmt.Fprintln(buf)
-	fmt.Fprintf(buf, "package %v\n", *p)
-
-	for _, k := range getOrderedFieldNames(aliases) {
-		a := aliases[k]
-
-		fmt.Fprintln(buf, "")
-		fmt.Fprintf(buf, "// %s\n", a.Name)
-		fmt.Fprintf(buf, "type %s %s\n", a.Name, a.Type)
+func output(w io.Writer, goPackage string, po generate.PackageOutput) {
+	tmpl, err := loadTemplate(*templateFile, *templateDir)
+	if err != nil {
+		log.Panicf("template error: %v\n", err)
 	}
 
-	for _, k := range getOrderedStructNames(structs) {
-		s := structs[k]
-
-		fmt.Fprintln(buf, "")
-		outputNameAndDescriptionComment(s.Name, s.Description, buf)
-		fmt.Fprintf(buf, "type %s struct {\n", s.Name)
-
-		for _, fieldKey := range getOrderedFieldNames(s.Fields) {
-			f := s.Fields[fieldKey]
-
-			// Only apply omitempty if the field is not required.
-			omitempty := ",omitempty"
-			if f.Required {
-				omitempty = ""
-			}
-
-			fmt.Fprintf(buf, "  %s %s `json:\"%s%s\"`\n", f.Name, f.Type, f.JSONName, omitempty)
-		}
+	data := buildTemplateData(goPackage, po, parseTagStyles(*tags))
 
-		fmt.Fprintln(buf, "}")
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		log.Panicf("template execution error: %v\n", err)
 	}
 
-	data, err := format.Source(buf.Bytes())
+	out, err := format.Source(buf.Bytes())
 	if err != nil {
 		log.Panicf("format error: %v\n", err)
 	}
 
-	w.Write(data)
+	w.Write(out)
 }
 
 func outputNameAndDescriptionComment(name, description string, w io.Writer) {
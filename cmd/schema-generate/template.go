@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/a-h/generate"
+)
+
+// templateData is the data model handed to output templates. Field names
+// are kept stable so that user-supplied templates (-template/-template-dir)
+// can rely on them.
+type templateData struct {
+	Package   string
+	TagStyles []string
+	Imports   []string
+	Aliases   []generate.Field
+	Structs   []templateStruct
+
+	// NeedsFmt and NeedsRegexp report whether any struct actually ends up
+	// with a Validate() method (which uses fmt.Errorf) or a Pattern
+	// constraint (which uses regexp), so those imports aren't emitted
+	// unused just because -tags was passed.
+	NeedsFmt    bool
+	NeedsRegexp bool
+}
+
+// templateStruct pairs a generate.Struct with its fields in the same
+// deterministic order that output has always used, since Go map iteration
+// order can't be relied on from within a template.
+type templateStruct struct {
+	generate.Struct
+	OrderedFields []generate.Field
+}
+
+// templateFuncs returns the FuncMap made available to every template,
+// whether it's the built-in default or one supplied via -template.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"lowercase": strings.ToLower,
+		"camelCase": lowerFirst,
+		"jsonTag": func(f generate.Field) string {
+			omitempty := ",omitempty"
+			if f.Required {
+				omitempty = ""
+			}
+			return fmt.Sprintf("json:\"%s%s\"", f.JSONName, omitempty)
+		},
+		"fieldTag": func(f generate.Field, tagStyles []string) string {
+			buf := &bytes.Buffer{}
+			omitempty := ",omitempty"
+			if f.Required {
+				omitempty = ""
+			}
+			writeFieldTag(buf, f, omitempty, tagStyles)
+			return buf.String()
+		},
+		"validateMethod": func(s generate.Struct, fieldKeys []string, tagStyles []string) string {
+			if tagStyles == nil {
+				return ""
+			}
+			buf := &bytes.Buffer{}
+			writeValidateMethod(buf, s, fieldKeys)
+			return buf.String()
+		},
+		"fieldKeys": func(s generate.Struct) []string {
+			return getOrderedFieldNames(s.Fields)
+		},
+	}
+}
+
+// defaultTemplateSource reproduces, byte for byte, the struct/alias layout
+// that output() used to build with fmt.Fprintf. newDefaultTemplate parses it
+// each time it's needed, i.e. whenever -template/-template-dir aren't given.
+const defaultTemplateSource = `// Code generated by schema-generate. DO NOT EDIT.
+
+package {{.Package}}
+{{if or .NeedsFmt .NeedsRegexp .Imports}}
+import (
+{{if .NeedsFmt}}  "fmt"
+{{end}}{{if .NeedsRegexp}}  "regexp"
+{{end}}{{range .Imports}}  "{{.}}"
+{{end}})
+{{end}}
+{{range .Aliases}}
+// {{.Name}}
+type {{.Name}} {{.Type}}
+{{end}}
+{{range .Structs}}
+{{descriptionComment .Name .Description}}
+type {{.Name}} struct {
+{{range .OrderedFields}}  {{.Name}} {{.Type}} ` + "`{{fieldTag . $.TagStyles}}`" + `
+{{end}}}
+
+{{validateMethod .Struct (fieldKeys .Struct) $.TagStyles}}
+{{end}}`
+
+func newDefaultTemplate() (*template.Template, error) {
+	funcs := templateFuncs()
+	funcs["descriptionComment"] = func(name, description string) string {
+		buf := &bytes.Buffer{}
+		outputNameAndDescriptionComment(name, description, buf)
+		return strings.TrimRight(buf.String(), "\n")
+	}
+	return template.New("default").Funcs(funcs).Parse(defaultTemplateSource)
+}
+
+// loadTemplate builds the template used to render generated code. When
+// templateFile or templateDir is set it's parsed from disk and the template
+// named "output" is the one executed, so the file (or one of the files in the
+// directory) must declare it with {{define "output"}}; a file without that
+// definition fails to execute as an empty template. Otherwise the built-in
+// default (which reproduces the historical fmt.Fprintf output) is used.
+func loadTemplate(templateFile, templateDir string) (*template.Template, error) {
+	if templateFile == "" && templateDir == "" {
+		return newDefaultTemplate()
+	}
+
+	funcs := templateFuncs()
+	t := template.New("output").Funcs(funcs)
+
+	if templateDir != "" {
+		return t.ParseGlob(templateDir + "/*")
+	}
+	return t.ParseFiles(templateFile)
+}
+
+// buildTemplateData converts a single package's output from
+// generate.CreateTypes into the deterministically ordered slices that
+// templates iterate over.
+func buildTemplateData(pkg string, po generate.PackageOutput, tagStyles []string) templateData {
+	data := templateData{Package: pkg, TagStyles: tagStyles, Imports: po.Imports}
+
+	for _, k := range getOrderedFieldNames(po.Aliases) {
+		data.Aliases = append(data.Aliases, po.Aliases[k])
+	}
+
+	for _, k := range getOrderedStructNames(po.Structs) {
+		s := po.Structs[k]
+		ts := templateStruct{Struct: s}
+		for _, fk := range getOrderedFieldNames(s.Fields) {
+			f := s.Fields[fk]
+			ts.OrderedFields = append(ts.OrderedFields, f)
+			if tagStyles != nil && needsValidateCheck(f.Constraints) {
+				data.NeedsFmt = true
+			}
+			if tagStyles != nil && f.Constraints.Pattern != "" {
+				data.NeedsRegexp = true
+			}
+		}
+		data.Structs = append(data.Structs, ts)
+	}
+
+	return data
+}
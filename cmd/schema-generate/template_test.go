@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"testing"
+
+	"github.com/a-h/generate"
+)
+
+// TestBuildTemplateDataOmitsUnusedImports makes sure fmt/regexp aren't
+// requested just because -tags was set: a struct with only a required
+// field (no constraints at all) never gets a Validate() method or a
+// Pattern, so neither import is needed.
+func TestBuildTemplateDataOmitsUnusedImports(t *testing.T) {
+	po := generate.PackageOutput{
+		Structs: map[string]generate.Struct{
+			"Widget": {
+				Name: "Widget",
+				Fields: map[string]generate.Field{
+					"name": {Name: "Name", Type: "string", JSONName: "name", Required: true},
+				},
+			},
+		},
+	}
+
+	data := buildTemplateData("main", po, []string{"validate"})
+
+	if data.NeedsFmt {
+		t.Errorf("expected NeedsFmt to be false when no field has constraints")
+	}
+	if data.NeedsRegexp {
+		t.Errorf("expected NeedsRegexp to be false when no field has a Pattern")
+	}
+}
+
+// TestBuildTemplateDataNeedsFmtAndRegexp makes sure the imports are
+// requested once a field actually has a constraint that needs them.
+func TestBuildTemplateDataNeedsFmtAndRegexp(t *testing.T) {
+	minLength := 1
+	po := generate.PackageOutput{
+		Structs: map[string]generate.Struct{
+			"Widget": {
+				Name: "Widget",
+				Fields: map[string]generate.Field{
+					"name": {
+						Name: "Name", Type: "string", JSONName: "name",
+						Constraints: generate.Constraints{MinLength: &minLength, Pattern: "^[a-z]+$"},
+					},
+				},
+			},
+		},
+	}
+
+	data := buildTemplateData("main", po, []string{"validate"})
+
+	if !data.NeedsFmt {
+		t.Errorf("expected NeedsFmt to be true when a field has constraints")
+	}
+	if !data.NeedsRegexp {
+		t.Errorf("expected NeedsRegexp to be true when a field has a Pattern")
+	}
+}
+
+// TestBuildTemplateDataFormatOnlyDoesNotNeedFmt makes sure a field whose
+// only constraint is Format doesn't set NeedsFmt: writeValidateMethod never
+// emits a check for Format on its own (it's surfaced as a struct tag
+// instead), so no Validate() method - and therefore no fmt.Errorf call -
+// is actually generated for it.
+func TestBuildTemplateDataFormatOnlyDoesNotNeedFmt(t *testing.T) {
+	po := generate.PackageOutput{
+		Structs: map[string]generate.Struct{
+			"Widget": {
+				Name: "Widget",
+				Fields: map[string]generate.Field{
+					"email": {
+						Name: "Email", Type: "string", JSONName: "email",
+						Constraints: generate.Constraints{Format: "email"},
+					},
+				},
+			},
+		},
+	}
+
+	data := buildTemplateData("main", po, []string{"validate"})
+
+	if data.NeedsFmt {
+		t.Errorf("expected NeedsFmt to be false for a format-only field")
+	}
+}
+
+// TestDefaultTemplateProducesValidGo renders a small PackageOutput through
+// the built-in default template and checks that go/format accepts the
+// result, i.e. it's syntactically valid Go.
+func TestDefaultTemplateProducesValidGo(t *testing.T) {
+	minLength := 1
+	po := generate.PackageOutput{
+		Structs: map[string]generate.Struct{
+			"Widget": {
+				Name:        "Widget",
+				Description: "Widget is a thing.",
+				Fields: map[string]generate.Field{
+					"name": {
+						Name: "Name", Type: "string", JSONName: "name", Required: true,
+						Constraints: generate.Constraints{MinLength: &minLength},
+					},
+				},
+			},
+		},
+	}
+
+	tmpl, err := newDefaultTemplate()
+	if err != nil {
+		t.Fatalf("newDefaultTemplate returned an error: %v", err)
+	}
+
+	data := buildTemplateData("models", po, []string{"validate"})
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("template execution failed: %v", err)
+	}
+
+	if _, err := format.Source(buf.Bytes()); err != nil {
+		t.Fatalf("expected valid Go output, got error %v, source:\n%s", err, buf.String())
+	}
+}
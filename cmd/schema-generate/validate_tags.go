@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/generate"
+)
+
+// parseTagStyles splits the -tags flag (e.g. "validate,binding") into the
+// list of extra struct tag keys that should be emitted alongside json.
+func parseTagStyles(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	var styles []string
+	for _, t := range strings.Split(tags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			styles = append(styles, t)
+		}
+	}
+	return styles
+}
+
+// buildValidatorRule turns a field's constraints into a go-playground/validator
+// rule string, e.g. "required,min=0,max=100,oneof=a b c".
+func buildValidatorRule(f generate.Field) string {
+	var rules []string
+	if f.Required {
+		rules = append(rules, "required")
+	}
+	c := f.Constraints
+	if c.Minimum != nil {
+		rules = append(rules, "min="+formatFloat(*c.Minimum))
+	}
+	if c.Maximum != nil {
+		rules = append(rules, "max="+formatFloat(*c.Maximum))
+	}
+	if c.MinLength != nil {
+		rules = append(rules, "min="+strconv.Itoa(*c.MinLength))
+	}
+	if c.MaxLength != nil {
+		rules = append(rules, "max="+strconv.Itoa(*c.MaxLength))
+	}
+	if c.Format != "" {
+		if tag, ok := validatorFormatTags[c.Format]; ok && tag != "" {
+			rules = append(rules, tag)
+		}
+	}
+	if len(c.Enum) > 0 {
+		values := make([]string, len(c.Enum))
+		for i, v := range c.Enum {
+			values[i] = fmt.Sprintf("%v", v)
+		}
+		rules = append(rules, "oneof="+strings.Join(values, " "))
+	}
+	return strings.Join(rules, ",")
+}
+
+// validatorFormatTags maps JSON Schema "format" values onto the closest
+// go-playground/validator tag.
+var validatorFormatTags = map[string]string{
+	"email":     "email",
+	"hostname":  "hostname",
+	"ipv4":      "ipv4",
+	"ipv6":      "ipv6",
+	"uri":       "uri",
+	"date-time": "",
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// writeFieldTag writes the full struct tag for a field, combining the
+// existing json tag with any extra tag styles requested via -tags.
+func writeFieldTag(buf *bytes.Buffer, f generate.Field, omitempty string, tagStyles []string) {
+	fmt.Fprintf(buf, "json:\"%s%s\"", f.JSONName, omitempty)
+
+	rule := buildValidatorRule(f)
+	for _, style := range tagStyles {
+		if rule == "" {
+			continue
+		}
+		fmt.Fprintf(buf, " %s:\"%s\"", style, rule)
+	}
+}
+
+// needsValidateCheck reports whether c carries a constraint that
+// writeValidateMethod actually turns into check code. Format alone doesn't -
+// it's only ever surfaced as a struct tag (buildValidatorRule) - so callers
+// deciding whether a Validate() method will be emitted must use this rather
+// than the broader Constraints.HasConstraints().
+func needsValidateCheck(c generate.Constraints) bool {
+	return c.Pattern != "" || c.Minimum != nil || c.Maximum != nil || c.MinLength != nil || c.MaxLength != nil || len(c.Enum) > 0
+}
+
+// writeValidateMethod emits a Validate() error method for s that enforces
+// the constraints carried by its fields. Pattern fields are backed by
+// package-level *regexp.Regexp vars so the pattern is compiled once, not on
+// every call.
+func writeValidateMethod(buf *bytes.Buffer, s generate.Struct, fieldKeys []string) bool {
+	type patternVar struct {
+		name, pattern string
+	}
+	var patterns []patternVar
+	var checks bytes.Buffer
+
+	for _, key := range fieldKeys {
+		f := s.Fields[key]
+		c := f.Constraints
+		if !needsValidateCheck(c) {
+			continue
+		}
+
+		if c.Pattern != "" {
+			varName := regexVarName(s.Name, f.Name)
+			patterns = append(patterns, patternVar{varName, c.Pattern})
+			fmt.Fprintf(&checks, "\tif !%s.MatchString(v.%s) {\n\t\treturn fmt.Errorf(\"%s: value does not match pattern %s\")\n\t}\n", varName, f.Name, f.Name, strconv.Quote(c.Pattern))
+		}
+		if c.Minimum != nil {
+			fmt.Fprintf(&checks, "\tif float64(v.%s) < %s {\n\t\treturn fmt.Errorf(\"%s: value is less than the minimum of %s\")\n\t}\n", f.Name, formatFloat(*c.Minimum), f.Name, formatFloat(*c.Minimum))
+		}
+		if c.Maximum != nil {
+			fmt.Fprintf(&checks, "\tif float64(v.%s) > %s {\n\t\treturn fmt.Errorf(\"%s: value is greater than the maximum of %s\")\n\t}\n", f.Name, formatFloat(*c.Maximum), f.Name, formatFloat(*c.Maximum))
+		}
+		if c.MinLength != nil {
+			fmt.Fprintf(&checks, "\tif len(v.%s) < %d {\n\t\treturn fmt.Errorf(\"%s: length is less than the minimum of %d\")\n\t}\n", f.Name, *c.MinLength, f.Name, *c.MinLength)
+		}
+		if c.MaxLength != nil {
+			fmt.Fprintf(&checks, "\tif len(v.%s) > %d {\n\t\treturn fmt.Errorf(\"%s: length is greater than the maximum of %d\")\n\t}\n", f.Name, *c.MaxLength, f.Name, *c.MaxLength)
+		}
+		if len(c.Enum) > 0 {
+			values := make([]string, len(c.Enum))
+			for i, v := range c.Enum {
+				values[i] = enumLiteral(f.Type, v)
+			}
+			fmt.Fprintf(&checks, "\tswitch v.%s {\n\tcase %s:\n\tdefault:\n\t\treturn fmt.Errorf(\"%s: value is not one of the allowed values\")\n\t}\n", f.Name, strings.Join(values, ", "), f.Name)
+		}
+	}
+
+	if checks.Len() == 0 {
+		return false
+	}
+
+	for _, p := range patterns {
+		fmt.Fprintf(buf, "var %s = regexp.MustCompile(%s)\n\n", p.name, strconv.Quote(p.pattern))
+	}
+
+	fmt.Fprintf(buf, "// Validate checks the constraints from the schema that %s was generated from.\n", s.Name)
+	fmt.Fprintf(buf, "func (v %s) Validate() error {\n", s.Name)
+	buf.Write(checks.Bytes())
+	fmt.Fprintln(buf, "\treturn nil")
+	fmt.Fprintln(buf, "}")
+	return true
+}
+
+// enumLiteral renders an enum value (decoded from JSON, so always a string,
+// float64, bool or nil) as a Go literal typed to match fieldType, so e.g. an
+// integer field with enum [1,2,3] gets unquoted case values instead of the
+// string literals that would fail to compare against an int.
+func enumLiteral(fieldType string, v interface{}) string {
+	switch fieldType {
+	case "string":
+		return fmt.Sprintf("%q", fmt.Sprintf("%v", v))
+	case "bool":
+		return fmt.Sprintf("%v", v)
+	case "float64":
+		if f, ok := v.(float64); ok {
+			return formatFloat(f)
+		}
+		return fmt.Sprintf("%v", v)
+	default:
+		// Integer field types: JSON numbers decode as float64, so convert
+		// to an integer literal rather than quoting or printing "1e+06".
+		if f, ok := v.(float64); ok {
+			return strconv.FormatInt(int64(f), 10)
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func regexVarName(structName, fieldName string) string {
+	return lowerFirst(structName) + fieldName + "Pattern"
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
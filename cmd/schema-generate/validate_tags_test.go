@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/a-h/generate"
+)
+
+// TestEnumLiteralTypesToField makes sure enum case values are rendered as
+// literals compatible with the field's Go type, not always as quoted
+// strings, since JSON numbers/bools decode as float64/bool.
+func TestEnumLiteralTypesToField(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		value     interface{}
+		want      string
+	}{
+		{"string", "a", `"a"`},
+		{"bool", true, "true"},
+		{"int", float64(3), "3"},
+		{"int64", float64(1000000), "1000000"},
+		{"float64", float64(1.5), "1.5"},
+	}
+
+	for _, tt := range tests {
+		if got := enumLiteral(tt.fieldType, tt.value); got != tt.want {
+			t.Errorf("enumLiteral(%q, %v) = %q, want %q", tt.fieldType, tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestBuildValidatorRuleDateTimeFormatOmitted makes sure a "date-time"
+// format - which has no corresponding go-playground/validator tag - doesn't
+// leave a dangling trailing comma in the rendered rule.
+func TestBuildValidatorRuleDateTimeFormatOmitted(t *testing.T) {
+	f := generate.Field{
+		Name: "OccurredAt", Type: "time.Time", JSONName: "occurredAt", Required: true,
+		Constraints: generate.Constraints{Format: "date-time"},
+	}
+
+	rule := buildValidatorRule(f)
+
+	if rule != "required" {
+		t.Errorf("expected rule to be %q, got %q", "required", rule)
+	}
+}
+
+// TestWriteValidateMethodIntEnumIsUnquoted makes sure an integer field with
+// an enum constraint generates a switch over unquoted numeric literals, so
+// the emitted code compiles against an int field.
+func TestWriteValidateMethodIntEnumIsUnquoted(t *testing.T) {
+	s := generate.Struct{
+		Name: "Widget",
+		Fields: map[string]generate.Field{
+			"level": {
+				Name: "Level",
+				Type: "int",
+				Constraints: generate.Constraints{
+					Enum: []interface{}{float64(1), float64(2), float64(3)},
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	ok := writeValidateMethod(buf, s, []string{"level"})
+	if !ok {
+		t.Fatalf("expected writeValidateMethod to emit a Validate method")
+	}
+
+	out := buf.String()
+	if strings.Contains(out, `case "1", "2", "3"`) {
+		t.Errorf("expected unquoted case values for an int field, got: %s", out)
+	}
+	if !strings.Contains(out, "case 1, 2, 3") {
+		t.Errorf("expected int case values 1, 2, 3, got: %s", out)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// isYAMLFile reports whether file should be treated as YAML, either because
+// the -yaml flag forces it or because of its extension.
+func isYAMLFile(file string) bool {
+	if *yamlInput {
+		return true
+	}
+	return strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml")
+}
+
+// yamlToJSON converts a YAML document into the equivalent JSON, so that it
+// can be handed to jsonschema.Parse or used as an instance document.
+func yamlToJSON(b []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(b, &value); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	return json.Marshal(normalizeYAML(value))
+}
+
+// normalizeYAML rewrites the map[interface{}]interface{} values produced by
+// yaml.v2 into map[string]interface{}, which is the only map type
+// encoding/json knows how to marshal.
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprintf("%v", k)] = normalizeYAML(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[k] = normalizeYAML(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = normalizeYAML(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
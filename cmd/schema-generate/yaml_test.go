@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIsYAMLFile(t *testing.T) {
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"schema.yaml", true},
+		{"schema.yml", true},
+		{"schema.json", false},
+	}
+
+	for _, tt := range tests {
+		if got := isYAMLFile(tt.file); got != tt.want {
+			t.Errorf("isYAMLFile(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+// TestYamlToJSON makes sure nested maps and sequences round-trip into JSON
+// with plain map[string]interface{} maps, since encoding/json can't
+// marshal the map[interface{}]interface{} maps yaml.v2 produces.
+func TestYamlToJSON(t *testing.T) {
+	input := []byte("name: widget\ncount: 3\ntags:\n  - a\n  - b\naddress:\n  zip: \"12345\"\n")
+
+	out, err := yamlToJSON(input)
+	if err != nil {
+		t.Fatalf("yamlToJSON returned an error: %v", err)
+	}
+
+	var value map[string]interface{}
+	if err := json.Unmarshal(out, &value); err != nil {
+		t.Fatalf("expected valid JSON, got error: %v, output: %s", err, out)
+	}
+
+	if value["name"] != "widget" {
+		t.Errorf("expected name to be widget, got %v", value["name"])
+	}
+	address, ok := value["address"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected address to be an object, got %T", value["address"])
+	}
+	if address["zip"] != "12345" {
+		t.Errorf("expected address.zip to be 12345, got %v", address["zip"])
+	}
+}
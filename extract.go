@@ -0,0 +1,244 @@
+package generate
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/generate/jsonschema"
+)
+
+// ExtractSchema is the inverse of CreateTypes: it walks the Go source in
+// pkgDir and produces the JSON Schema describing the exported structs it
+// finds there, so that hand-maintained Go types can be treated as the
+// source of truth instead of a schema file. Doc comments become
+// "description", and a `jsonschema:"..."` struct tag (e.g.
+// `jsonschema:"minimum=0,maximum=100,enum=a|b|c"`) supplies the constraints
+// that can't be recovered from the Go type alone.
+//
+// Every exported struct found in the package is added to the returned
+// schema's "definitions", keyed by its Go name.
+func ExtractSchema(pkgDir string) (*jsonschema.Schema, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkgDir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &jsonschema.Schema{Definitions: make(map[string]*jsonschema.Schema)}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range genDecl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok || !typeSpec.Name.IsExported() {
+						continue
+					}
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+
+					schema := structSchema(structType)
+					doc := typeSpec.Doc
+					if doc == nil && !genDecl.Lparen.IsValid() {
+						doc = genDecl.Doc
+					}
+					schema.Description = docText(doc)
+					root.Definitions[typeSpec.Name.Name] = schema
+				}
+			}
+		}
+	}
+
+	return root, nil
+}
+
+func structSchema(structType *ast.StructType) *jsonschema.Schema {
+	schema := &jsonschema.Schema{Type: "object", Properties: make(map[string]*jsonschema.Schema)}
+
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 || !field.Names[0].IsExported() {
+			continue
+		}
+
+		jsonName, omitempty := jsonFieldName(field)
+		if jsonName == "-" {
+			continue
+		}
+
+		propSchema := typeSchema(field.Type)
+		propSchema.Description = docText(field.Doc)
+		applyJSONSchemaTag(propSchema, field)
+
+		schema.Properties[jsonName] = propSchema
+		if !omitempty {
+			schema.Required = append(schema.Required, jsonName)
+		}
+	}
+
+	return schema
+}
+
+func typeSchema(expr ast.Expr) *jsonschema.Schema {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return typeSchema(t.X)
+	case *ast.ArrayType:
+		return &jsonschema.Schema{Type: "array", Items: typeSchema(t.Elt)}
+	case *ast.StructType:
+		return structSchema(t)
+	case *ast.SelectorExpr:
+		if ident, ok := t.X.(*ast.Ident); ok && ident.Name == "time" && t.Sel.Name == "Time" {
+			return &jsonschema.Schema{Type: "string", Format: "date-time"}
+		}
+		return &jsonschema.Schema{}
+	case *ast.Ident:
+		return &jsonschema.Schema{Type: goTypeToJSONType(t.Name)}
+	default:
+		return &jsonschema.Schema{}
+	}
+}
+
+func goTypeToJSONType(name string) string {
+	switch name {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	default:
+		return "object"
+	}
+}
+
+// jsonFieldName reads the field's `json:"..."` tag (falling back to its Go
+// name) and reports whether the tag carries the omitempty option.
+func jsonFieldName(field *ast.Field) (name string, omitempty bool) {
+	name = field.Names[0].Name
+	if field.Tag == nil {
+		return name, false
+	}
+
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return name, false
+	}
+
+	jsonTag := readStructTag(tag, "json")
+	if jsonTag == "" {
+		return name, false
+	}
+
+	parts := strings.Split(jsonTag, ",")
+	if parts[0] != "" {
+		name = parts[0]
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// applyJSONSchemaTag reads a `jsonschema:"minimum=0,maximum=100,enum=a|b|c"`
+// struct tag and applies the constraints it describes to schema.
+func applyJSONSchemaTag(schema *jsonschema.Schema, field *ast.Field) {
+	if field.Tag == nil {
+		return
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+	jsonschemaTag := readStructTag(tag, "jsonschema")
+	if jsonschemaTag == "" {
+		return
+	}
+
+	for _, constraint := range strings.Split(jsonschemaTag, ",") {
+		kv := strings.SplitN(constraint, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := kv[0], kv[1]
+
+		switch key {
+		case "minimum":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				schema.Minimum = &f
+			}
+		case "maximum":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				schema.Maximum = &f
+			}
+		case "minLength":
+			if n, err := strconv.Atoi(value); err == nil {
+				schema.MinLength = &n
+			}
+		case "maxLength":
+			if n, err := strconv.Atoi(value); err == nil {
+				schema.MaxLength = &n
+			}
+		case "pattern":
+			schema.Pattern = value
+		case "format":
+			schema.Format = value
+		case "enum":
+			for _, v := range strings.Split(value, "|") {
+				schema.Enum = append(schema.Enum, v)
+			}
+		}
+	}
+}
+
+// readStructTag is a small stand-in for reflect.StructTag.Get that works
+// against the raw tag text the go/ast parser gives us.
+func readStructTag(tag, key string) string {
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " \t")
+		if tag == "" {
+			break
+		}
+		i := strings.Index(tag, ":")
+		if i < 0 {
+			break
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+		if len(tag) == 0 || tag[0] != '"' {
+			break
+		}
+		tag = tag[1:]
+		j := strings.Index(tag, `"`)
+		if j < 0 {
+			break
+		}
+		value := tag[:j]
+		tag = tag[j+1:]
+		if name == key {
+			return value
+		}
+	}
+	return ""
+}
+
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
@@ -0,0 +1,70 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const extractFixtureSource = `package fixture
+
+// Widget is a thing.
+type Widget struct {
+	// Name is the widget's name.
+	Name  string ` + "`json:\"name\" jsonschema:\"minLength=1,maxLength=10\"`" + `
+	Count int    ` + "`json:\"count,omitempty\" jsonschema:\"minimum=0,maximum=100\"`" + `
+	inner string
+}
+`
+
+// TestExtractSchema makes sure ExtractSchema recovers the exported struct,
+// its json field names/omitempty-derived required list, and the
+// constraints carried in the jsonschema struct tag.
+func TestExtractSchema(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "widget.go"), []byte(extractFixtureSource), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	schema, err := ExtractSchema(dir)
+	if err != nil {
+		t.Fatalf("ExtractSchema returned an error: %v", err)
+	}
+
+	widget, ok := schema.Definitions["Widget"]
+	if !ok {
+		t.Fatalf("expected a Widget definition, got %v", schema.Definitions)
+	}
+	if widget.Description != "Widget is a thing." {
+		t.Errorf("expected the doc comment as description, got %q", widget.Description)
+	}
+
+	name, ok := widget.Properties["name"]
+	if !ok {
+		t.Fatalf("expected a name property, got %v", widget.Properties)
+	}
+	if name.MinLength == nil || *name.MinLength != 1 {
+		t.Errorf("expected name.minLength to be 1, got %v", name.MinLength)
+	}
+	if name.MaxLength == nil || *name.MaxLength != 10 {
+		t.Errorf("expected name.maxLength to be 10, got %v", name.MaxLength)
+	}
+	if !contains(widget.Required, "name") {
+		t.Errorf("expected name to be required, got %v", widget.Required)
+	}
+
+	count, ok := widget.Properties["count"]
+	if !ok {
+		t.Fatalf("expected a count property, got %v", widget.Properties)
+	}
+	if count.Minimum == nil || *count.Minimum != 0 {
+		t.Errorf("expected count.minimum to be 0, got %v", count.Minimum)
+	}
+	if contains(widget.Required, "count") {
+		t.Errorf("expected count to not be required due to omitempty, got %v", widget.Required)
+	}
+
+	if _, ok := widget.Properties["inner"]; ok {
+		t.Errorf("expected the unexported field inner to be skipped, got %v", widget.Properties)
+	}
+}
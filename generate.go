@@ -0,0 +1,310 @@
+// Package generate turns parsed JSON Schema documents into the data model
+// (structs, fields and aliases) used to render Go source.
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a-h/generate/jsonschema"
+)
+
+// Field describes a single field of a generated struct.
+type Field struct {
+	Name     string
+	JSONName string
+	Type     string
+	Required bool
+
+	// Constraints carries the subset of JSON Schema validation keywords
+	// that apply to this field, so that output can emit validator tags
+	// and a Validate() method from them.
+	Constraints Constraints
+}
+
+// Constraints holds the JSON Schema validation keywords that can be
+// translated into go-playground/validator struct tags and Validate()
+// method bodies.
+type Constraints struct {
+	Minimum   *float64
+	Maximum   *float64
+	MinLength *int
+	MaxLength *int
+	Pattern   string
+	Format    string
+	Enum      []interface{}
+}
+
+// HasConstraints reports whether any validation keyword was set on the
+// field's schema.
+func (c Constraints) HasConstraints() bool {
+	return c.Minimum != nil || c.Maximum != nil || c.MinLength != nil || c.MaxLength != nil || c.Pattern != "" || c.Format != "" || len(c.Enum) > 0
+}
+
+// Struct describes a Go struct derived from a JSON Schema object.
+type Struct struct {
+	ID          string
+	Name        string
+	Description string
+	Fields      map[string]Field
+}
+
+// PackageOutput is everything generated for a single output package: its
+// structs, its top-level aliases, and the import paths that its generated
+// code needs (including any pulled in by cross-package $ref fields).
+type PackageOutput struct {
+	Structs map[string]Struct
+	Aliases map[string]Field
+	Imports []string
+}
+
+// DefaultPackage is the PackageOutput key used for schemas that aren't
+// assigned an explicit package via SetSchemaPackages.
+const DefaultPackage = ""
+
+// Generator walks one or more parsed JSON Schemas and produces the Go
+// struct and alias definitions that describe them.
+type Generator struct {
+	schemas        []*jsonschema.Schema
+	schemaPackages map[string]string
+}
+
+// New creates a Generator for the given schemas.
+func New(schemas ...*jsonschema.Schema) *Generator {
+	return &Generator{schemas: schemas}
+}
+
+// SetSchemaPackages records, by schema URI, which output package each root
+// schema's structs should be generated into. Schemas whose URI isn't
+// present fall back to DefaultPackage.
+func (g *Generator) SetSchemaPackages(packages map[string]string) {
+	g.schemaPackages = packages
+}
+
+// CreateTypes walks the schemas held by the Generator and returns the
+// generated structs and aliases, grouped by output package.
+func (g *Generator) CreateTypes() (map[string]PackageOutput, error) {
+	packages := make(map[string]PackageOutput)
+
+	for _, schema := range g.schemas {
+		pkg := g.schemaPackages[schema.URI]
+		po := packages[pkg]
+		if po.Structs == nil {
+			po.Structs = make(map[string]Struct)
+			po.Aliases = make(map[string]Field)
+		}
+
+		if err := g.createTypesFromSchema(schema, pkg, &po); err != nil {
+			return nil, err
+		}
+
+		packages[pkg] = po
+	}
+
+	return packages, nil
+}
+
+func (g *Generator) createTypesFromSchema(schema *jsonschema.Schema, pkg string, po *PackageOutput) error {
+	// Definitions are only ever reached through a $ref (resolveFieldType
+	// names the field type after the $ref's last segment), but they still
+	// need their own struct
+	// generated, so walk them here regardless of whether this schema
+	// itself turns into a struct.
+	for defName, def := range schema.Definitions {
+		if err := g.createNamedStruct(def, capitalize(defName), pkg, po); err != nil {
+			return err
+		}
+	}
+
+	if len(schema.Properties) == 0 {
+		return nil
+	}
+
+	return g.createNamedStruct(schema, getSchemaName(schema), pkg, po)
+}
+
+func (g *Generator) createNamedStruct(schema *jsonschema.Schema, name string, pkg string, po *PackageOutput) error {
+	if len(schema.Properties) == 0 {
+		return nil
+	}
+
+	s := Struct{
+		ID:          schema.ID,
+		Name:        name,
+		Description: schema.Description,
+		Fields:      make(map[string]Field),
+	}
+
+	for propName, propSchema := range schema.Properties {
+		fieldType, imp, err := g.resolveFieldType(schema, propSchema, pkg)
+		if err != nil {
+			return err
+		}
+		if imp != "" {
+			po.Imports = appendUnique(po.Imports, imp)
+		}
+		if propSchema.Format == "date-time" {
+			po.Imports = appendUnique(po.Imports, "time")
+		}
+
+		constraints := Constraints{
+			Minimum:   propSchema.Minimum,
+			Maximum:   propSchema.Maximum,
+			MinLength: propSchema.MinLength,
+			MaxLength: propSchema.MaxLength,
+			Pattern:   propSchema.Pattern,
+			Format:    propSchema.Format,
+			Enum:      propSchema.Enum,
+		}
+		if fieldType == "time.Time" {
+			// MinLength/MaxLength/Pattern/Enum are checks against a string
+			// representation, which time.Time no longer has once the field
+			// is generated as a proper time.Time rather than a string.
+			constraints.MinLength = nil
+			constraints.MaxLength = nil
+			constraints.Pattern = ""
+			constraints.Enum = nil
+		}
+
+		s.Fields[propName] = Field{
+			Name:        capitalize(propName),
+			JSONName:    propName,
+			Type:        fieldType,
+			Required:    contains(schema.Required, propName),
+			Constraints: constraints,
+		}
+
+		if err := g.createTypesFromSchema(propSchema, pkg, po); err != nil {
+			return err
+		}
+	}
+
+	po.Structs[name] = s
+	return nil
+}
+
+// resolveFieldType returns the Go type to use for propSchema, resolving
+// $ref against the schemas known to the Generator. When the $ref points
+// into a schema routed to a different package, the returned type is
+// qualified with that package's name and the second return value is the
+// import path that needs to be added to the referencing package.
+func (g *Generator) resolveFieldType(parent, propSchema *jsonschema.Schema, pkg string) (fieldType string, importPath string, err error) {
+	if propSchema.Ref == "" {
+		return getPrimitiveType(propSchema), "", nil
+	}
+
+	refURI, refName := splitRef(propSchema.Ref)
+	if refURI == "" || refURI == parent.Root().URI {
+		return capitalize(refName), "", nil
+	}
+
+	refPkg, ok := g.schemaPackages[refURI]
+	if !ok {
+		// A known schema without a package entry lives in DefaultPackage.
+		for _, s := range g.schemas {
+			if s.URI == refURI {
+				ok = true
+			}
+		}
+	}
+	if !ok || (refPkg == DefaultPackage && pkg != DefaultPackage) {
+		return "", "", errUnresolvedRef(propSchema.Ref)
+	}
+	if refPkg == pkg {
+		return capitalize(refName), "", nil
+	}
+
+	return PackageAlias(refPkg) + "." + capitalize(refName), refPkg, nil
+}
+
+// splitRef splits a "uri#/definitions/Name"-style $ref into the URI of the
+// schema it points at and the definition name. A ref with no URI portion
+// (e.g. "#/definitions/Name") refers to the current schema.
+func splitRef(ref string) (uri, name string) {
+	parts := strings.SplitN(ref, "#", 2)
+	uri = parts[0]
+	if len(parts) == 2 {
+		segments := strings.Split(parts[1], "/")
+		name = segments[len(segments)-1]
+	}
+	return uri, name
+}
+
+// PackageAlias returns the Go package name for an import path, i.e. its
+// last path segment, used both to qualify cross-package field types and to
+// pick the "package X" declaration for a routed schema.
+func PackageAlias(pkg string) string {
+	segments := strings.Split(pkg, "/")
+	return segments[len(segments)-1]
+}
+
+func appendUnique(list []string, item string) []string {
+	for _, v := range list {
+		if v == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
+
+func getSchemaName(schema *jsonschema.Schema) string {
+	if schema.Title != "" {
+		return capitalize(schema.Title)
+	}
+	if schema.ID != "" {
+		return capitalize(schema.ID)
+	}
+	return "Root"
+}
+
+func getPrimitiveType(schema *jsonschema.Schema) string {
+	types := schema.TypeList()
+	if len(types) == 0 {
+		return "interface{}"
+	}
+
+	switch types[0] {
+	case "string":
+		if schema.Format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "object":
+		return getSchemaName(schema)
+	case "array":
+		if schema.Items != nil {
+			return "[]" + getPrimitiveType(schema.Items)
+		}
+		return "[]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// errUnresolvedRef is returned when a $ref cannot be resolved against the
+// schemas known to the Generator.
+func errUnresolvedRef(ref string) error {
+	return fmt.Errorf("generate: unresolved $ref %q", ref)
+}
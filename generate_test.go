@@ -0,0 +1,177 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/a-h/generate/jsonschema"
+)
+
+const definitionsSchema = `{
+  "title": "person",
+  "type": "object",
+  "properties": {
+    "name": { "type": "string" },
+    "address": { "$ref": "#/definitions/address" }
+  },
+  "required": ["name"],
+  "definitions": {
+    "address": {
+      "type": "object",
+      "properties": {
+        "line1": { "type": "string" }
+      },
+      "required": ["line1"]
+    }
+  }
+}`
+
+// TestCreateTypesResolvesDefinitions makes sure a $ref to a local
+// "definitions" entry both resolves to the right field type and causes the
+// referenced struct to actually be generated - the most common JSON Schema
+// composition idiom.
+func TestCreateTypesResolvesDefinitions(t *testing.T) {
+	schema, err := jsonschema.Parse(definitionsSchema)
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	g := New(schema)
+	packages, err := g.CreateTypes()
+	if err != nil {
+		t.Fatalf("CreateTypes returned an error: %v", err)
+	}
+
+	po := packages[DefaultPackage]
+
+	person, ok := po.Structs["Person"]
+	if !ok {
+		t.Fatalf("expected a Person struct, got %v", structNames(po))
+	}
+
+	addressField, ok := person.Fields["address"]
+	if !ok {
+		t.Fatalf("expected Person to have an address field, got %v", fieldNames(person))
+	}
+	if addressField.Type != "Address" {
+		t.Errorf("expected the address field's type to be Address, got %q", addressField.Type)
+	}
+
+	if _, ok := po.Structs["Address"]; !ok {
+		t.Fatalf("expected an Address struct to be generated from the definition, got %v", structNames(po))
+	}
+}
+
+// TestCreateTypesMapsDateTimeToTimeTime makes sure a "format": "date-time"
+// string property is generated as time.Time (with the "time" import
+// PackageOutput already adds for it actually put to use), and that
+// length/pattern/enum constraints - which don't apply to time.Time - aren't
+// carried over onto the field.
+func TestCreateTypesMapsDateTimeToTimeTime(t *testing.T) {
+	schema, err := jsonschema.Parse(`{
+		"title": "event",
+		"type": "object",
+		"properties": {
+			"occurredAt": { "type": "string", "format": "date-time", "minLength": 10 }
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	g := New(schema)
+	packages, err := g.CreateTypes()
+	if err != nil {
+		t.Fatalf("CreateTypes returned an error: %v", err)
+	}
+
+	po := packages[DefaultPackage]
+	event, ok := po.Structs["Event"]
+	if !ok {
+		t.Fatalf("expected an Event struct, got %v", structNames(po))
+	}
+
+	field, ok := event.Fields["occurredAt"]
+	if !ok {
+		t.Fatalf("expected Event to have an occurredAt field, got %v", fieldNames(event))
+	}
+	if field.Type != "time.Time" {
+		t.Errorf("expected the occurredAt field's type to be time.Time, got %q", field.Type)
+	}
+	if field.Constraints.MinLength != nil {
+		t.Errorf("expected MinLength to be dropped for a time.Time field, got %v", field.Constraints.MinLength)
+	}
+	if !contains(po.Imports, "time") {
+		t.Errorf("expected the time package to be imported, got %v", po.Imports)
+	}
+}
+
+// TestCreateTypesRoutesCrossSchemaImports makes sure a $ref that points at
+// a schema routed to a different package is rendered as a qualified type
+// (pkg.Name) and pulls that package's import path into the referencing
+// package's PackageOutput.
+func TestCreateTypesRoutesCrossSchemaImports(t *testing.T) {
+	personSchema, err := jsonschema.Parse(`{
+		"title": "person",
+		"type": "object",
+		"properties": {
+			"address": { "$ref": "address.json#/definitions/address" }
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("failed to parse person schema: %v", err)
+	}
+	personSchema.URI = "person.json"
+
+	addressSchema, err := jsonschema.Parse(`{
+		"title": "address",
+		"type": "object",
+		"properties": {
+			"line1": { "type": "string" }
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("failed to parse address schema: %v", err)
+	}
+	addressSchema.URI = "address.json"
+
+	g := New(personSchema, addressSchema)
+	g.SetSchemaPackages(map[string]string{"address.json": "example.com/models/address"})
+
+	packages, err := g.CreateTypes()
+	if err != nil {
+		t.Fatalf("CreateTypes returned an error: %v", err)
+	}
+
+	po := packages[DefaultPackage]
+	person, ok := po.Structs["Person"]
+	if !ok {
+		t.Fatalf("expected a Person struct, got %v", structNames(po))
+	}
+
+	addressField, ok := person.Fields["address"]
+	if !ok {
+		t.Fatalf("expected Person to have an address field, got %v", fieldNames(person))
+	}
+	if addressField.Type != "address.Address" {
+		t.Errorf("expected the address field's type to be qualified as address.Address, got %q", addressField.Type)
+	}
+	if !contains(po.Imports, "example.com/models/address") {
+		t.Errorf("expected the address package to be imported, got %v", po.Imports)
+	}
+}
+
+func structNames(po PackageOutput) []string {
+	names := make([]string, 0, len(po.Structs))
+	for name := range po.Structs {
+		names = append(names, name)
+	}
+	return names
+}
+
+func fieldNames(s Struct) []string {
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	return names
+}
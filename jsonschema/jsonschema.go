@@ -0,0 +1,94 @@
+// Package jsonschema parses JSON Schema documents into a tree of Go values
+// that the generate package can walk to produce Go types.
+package jsonschema
+
+import "encoding/json"
+
+// Schema represents a JSON Schema document, or a sub-schema nested within
+// one (e.g. through "properties" or "items").
+type Schema struct {
+	ID          string             `json:"id,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        interface{}        `json:"type,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+
+	// Minimum, Maximum and friends constrain the values that an instance
+	// of this schema may take. They are kept as pointers so that a missing
+	// keyword can be distinguished from an explicit zero value.
+	Minimum   *float64      `json:"minimum,omitempty"`
+	Maximum   *float64      `json:"maximum,omitempty"`
+	MinLength *int          `json:"minLength,omitempty"`
+	MaxLength *int          `json:"maxLength,omitempty"`
+	Pattern   string        `json:"pattern,omitempty"`
+	Format    string        `json:"format,omitempty"`
+	Enum      []interface{} `json:"enum,omitempty"`
+
+	Parent *Schema `json:"-"`
+
+	// URI identifies where this (root) schema was loaded from, e.g. a file
+	// path or URL. It is not part of the JSON Schema itself - callers set
+	// it after Parse so that $ref values can be resolved across schemas
+	// and so that generated structs can be routed to the right package.
+	URI string `json:"-"`
+}
+
+// Parse converts a string of JSON data into a Schema, wiring up the Parent
+// pointers on every nested sub-schema so that callers can walk back up the
+// tree (e.g. when resolving relative $ref values).
+func Parse(schema string) (*Schema, error) {
+	s := &Schema{}
+	err := json.Unmarshal([]byte(schema), s)
+	if err != nil {
+		return nil, err
+	}
+	s.setParent(nil)
+	return s, nil
+}
+
+func (s *Schema) setParent(parent *Schema) {
+	s.Parent = parent
+	for _, child := range s.Properties {
+		child.setParent(s)
+	}
+	if s.Items != nil {
+		s.Items.setParent(s)
+	}
+	for _, def := range s.Definitions {
+		def.setParent(s)
+	}
+}
+
+// Root walks up the Parent chain and returns the outermost schema, i.e.
+// the one that was handed to Parse and has URI set.
+func (s *Schema) Root() *Schema {
+	root := s
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	return root
+}
+
+// TypeList returns the possible JSON types for the schema. The "type"
+// keyword may be a single string or an array of strings, so this normalises
+// both forms.
+func (s *Schema) TypeList() []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			if str, ok := v.(string); ok {
+				types = append(types, str)
+			}
+		}
+		return types
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,249 @@
+// Package generate also exposes a small Draft-07 style validator so that
+// callers can check JSON instance documents against the same schema that
+// was used to generate Go types, without depending on a separate library.
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/generate/jsonschema"
+)
+
+// ValidationError describes a single constraint violation found while
+// validating a JSON instance document against a Schema.
+type ValidationError struct {
+	// Path is the JSON Pointer-ish dotted path to the offending value,
+	// e.g. "address.postCode".
+	Path string `json:"path"`
+	// Message is a human readable description of the failure.
+	Message string `json:"message"`
+	// Offset is the byte offset of the offending value within the
+	// original instance document, suitable for passing to a
+	// line/character lookup such as the CLI's lineAndCharacter helper.
+	Offset int `json:"offset"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate checks the instance document against schema and returns one
+// ValidationError per constraint violation found. A nil/empty slice means
+// the instance is valid.
+func Validate(schema *jsonschema.Schema, instance []byte) ([]ValidationError, error) {
+	var value interface{}
+	if err := json.Unmarshal(instance, &value); err != nil {
+		return nil, fmt.Errorf("generate: instance is not valid JSON: %w", err)
+	}
+
+	v := &validator{instance: instance}
+	v.walk("", schema, value)
+	return v.errors, nil
+}
+
+type validator struct {
+	instance []byte
+	errors   []ValidationError
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, ValidationError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+		Offset:  v.locate(path),
+	})
+}
+
+// locate makes a best-effort attempt to find the byte offset of the field
+// named by the last segment of path within the original instance document.
+// It returns 0 (the start of the document) if the field can't be found,
+// which is preferable to reporting a misleading position.
+func (v *validator) locate(path string) int {
+	if path == "" {
+		return 0
+	}
+	segments := strings.Split(path, ".")
+	needle := []byte(`"` + segments[len(segments)-1] + `"`)
+	if idx := bytes.Index(v.instance, needle); idx >= 0 {
+		return idx
+	}
+	return 0
+}
+
+func (v *validator) walk(path string, schema *jsonschema.Schema, value interface{}) {
+	schema = resolveSchema(schema)
+	if schema == nil {
+		return
+	}
+
+	if !v.checkType(path, schema, value) {
+		return
+	}
+
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		v.fail(path, "value is not one of the allowed enum values")
+	}
+
+	switch val := value.(type) {
+	case string:
+		v.checkString(path, schema, val)
+	case float64:
+		v.checkNumber(path, schema, val)
+	case map[string]interface{}:
+		v.checkObject(path, schema, val)
+	case []interface{}:
+		v.checkArray(path, schema, val)
+	}
+}
+
+func (v *validator) checkType(path string, schema *jsonschema.Schema, value interface{}) bool {
+	types := schema.TypeList()
+	if len(types) == 0 {
+		return true
+	}
+
+	for _, t := range types {
+		if jsonTypeMatches(t, value) {
+			return true
+		}
+	}
+
+	v.fail(path, "expected type %s but got %s", strings.Join(types, " or "), jsonTypeName(value))
+	return false
+}
+
+func jsonTypeMatches(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case nil:
+		return "null"
+	default:
+		return "unknown"
+	}
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) checkString(path string, schema *jsonschema.Schema, value string) {
+	if schema.MinLength != nil && len(value) < *schema.MinLength {
+		v.fail(path, "length %d is less than minLength %d", len(value), *schema.MinLength)
+	}
+	if schema.MaxLength != nil && len(value) > *schema.MaxLength {
+		v.fail(path, "length %d is greater than maxLength %d", len(value), *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		re, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			v.fail(path, "schema pattern %q is not a valid regular expression", schema.Pattern)
+			return
+		}
+		if !re.MatchString(value) {
+			v.fail(path, "value does not match pattern %q", schema.Pattern)
+		}
+	}
+}
+
+func (v *validator) checkNumber(path string, schema *jsonschema.Schema, value float64) {
+	if schema.Minimum != nil && value < *schema.Minimum {
+		v.fail(path, "%v is less than minimum %v", value, *schema.Minimum)
+	}
+	if schema.Maximum != nil && value > *schema.Maximum {
+		v.fail(path, "%v is greater than maximum %v", value, *schema.Maximum)
+	}
+}
+
+func (v *validator) checkObject(path string, schema *jsonschema.Schema, value map[string]interface{}) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.fail(joinPath(path, name), "required property %q is missing", name)
+		}
+	}
+
+	for name, propSchema := range schema.Properties {
+		propValue, ok := value[name]
+		if !ok {
+			continue
+		}
+		v.walk(joinPath(path, name), propSchema, propValue)
+	}
+}
+
+func (v *validator) checkArray(path string, schema *jsonschema.Schema, value []interface{}) {
+	if schema.Items == nil {
+		return
+	}
+	for i, item := range value {
+		v.walk(fmt.Sprintf("%s[%d]", path, i), schema.Items, item)
+	}
+}
+
+// resolveSchema follows a $ref to the schema it points at, looking it up
+// in the root schema's "definitions" - the same composition idiom
+// CreateTypes resolves when generating structs. A $ref that can't be
+// resolved is left as-is, so validation just falls through without
+// applying any constraints from it.
+func resolveSchema(schema *jsonschema.Schema) *jsonschema.Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+
+	_, name := splitRef(schema.Ref)
+	if def, ok := schema.Root().Definitions[name]; ok {
+		return def
+	}
+	return schema
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,52 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/a-h/generate/jsonschema"
+)
+
+const addressRefSchema = `{
+  "title": "person",
+  "type": "object",
+  "properties": {
+    "address": { "$ref": "#/definitions/address" }
+  },
+  "definitions": {
+    "address": {
+      "type": "object",
+      "properties": {
+        "zip": { "type": "string", "minLength": 5 }
+      }
+    }
+  }
+}`
+
+// TestValidateResolvesRef makes sure constraints nested behind a $ref into
+// "definitions" are actually enforced, matching what an inline (non-$ref)
+// schema would enforce.
+func TestValidateResolvesRef(t *testing.T) {
+	schema, err := jsonschema.Parse(addressRefSchema)
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	errs, err := Validate(schema, []byte(`{"address":{"zip":"1"}}`))
+	if err != nil {
+		t.Fatalf("Validate returned an error: %v", err)
+	}
+
+	if len(errs) == 0 {
+		t.Fatalf("expected a minLength violation on address.zip, got no errors")
+	}
+
+	found := false
+	for _, e := range errs {
+		if e.Path == "address.zip" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an error for address.zip, got %+v", errs)
+	}
+}